@@ -1,41 +1,45 @@
 package main
 
 import (
-	"bufio"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/google/uuid"
+
+	"goplay/internal/archive"
+	"goplay/internal/playground"
+	"goplay/internal/runner"
+	"goplay/internal/store"
+	"goplay/internal/templates"
 )
 
 // TODO: consider permissions.
 // TODO: also make sure opened files are closed in defer.
 
-const helpMessage = `GoPlay Use: 
+const helpMessage = `GoPlay Use:
 1: 'goplay', creates a new directory under goplay directory. Goplay directory is set by environment variable GOPLAY_DIR, or defaults to '~/.goplay'. Then, opens the directory using your code editor command; this is set in GOPLAY_CODE_CMD, and defaults to 'code'.
 2: 'goplay name', creates a new directory named 'name', as above (without a name provided, it makes it a uuid.) Name cannot be an integer.
 3: 'goplay -d', deletes most recent goplay directory.
 4: 'goplay -d name', deletes directory named 'name'
 5: 'goplay -d 2', deletes last 2 directories; name cannot be an integer.
 6: 'goplay -D', deletes all directories, with confirmation.
-7: 'goplay --help', displays this help information.`
-
-const programContents = `package main
-
-import (
-	"fmt"
-)
-
-func main() {
-	fmt.Println("Hello world")
-}
-`
+7: 'goplay --help', displays this help information.
+8: 'goplay run [name]', 'goplay build [name]', 'goplay test [name]': chdir into the playground (defaulting to the most recent one) and run the matching go toolchain command, streaming its output and exit code back.
+9: 'goplay -t template [name]', creates a new directory as above, seeded from the named starter template instead of the default hello-world program. Built-in templates: %s. Drop a directory into $GOPLAY_DIR/templates/<name> to add your own.
+10: 'goplay ls [--json] [--sort=age|size|name]', lists every playground with its path, creation time, size on disk, and whether it still exists.
+11: 'goplay -d --older-than 30d', 'goplay -d --larger-than 50M', deletes playgrounds matching the given retention policy instead of by name or count.
+12: 'goplay test --all [-n N] [-shard i -shards K] [-v] [--timeout dur]', runs 'go test ./...' across every recorded playground concurrently and prints a pass/fail summary.
+13: 'goplay -d/-D --archive', tars each playground into $GOPLAY_DIR/archive/<name>-<timestamp>.tar.gz before deleting it. 'goplay -d/-D --dry-run' prints what would be deleted without touching disk. 'goplay restore <archive>' reconstitutes an archive as a new playground.`
 
 // Default will get home added to front of it. Cannot just use ~
 const defaultDir = ".goplay"
@@ -59,29 +63,12 @@ func getCodeCMD() string {
 	return defaultCodeCMD
 }
 
-// Following ensures setup and returns the file tracking created directories. Returned file can be appended to or read.
-// It is callers responsibility to close returned file.
-func setupHomeDir(dir string) (*os.File, error) {
-	// Need to check if directory exists. If it does not, create it.
-	// Then, check that the .generated_dirs file exists. If not, create it.
-	// Easiest to just try to make dir and check for error.
-	if err := os.Mkdir(dir, 0777); err != nil && !errors.Is(err, os.ErrExist) {
-		return nil, err
-	}
-
-	// Now we want to open and return the file.
-	filePath := path.Join(dir, ".generated_dirs")
-	// If the file doesn't exist, create it, or append to the file
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0777) // Will be closed later.
-	if err != nil {
-		panic(err)
-	}
-	return f, nil
-}
-
-// Following assumes that the homeDir is set up and the genFiles list has been passed in correctly.
-// Following creates the directory under the home directory. Need to make the directory, add the name to the .generated_dirs file, and add a marker file in the directory (for ensuring do not delete wrong folder later). Then run go mod init on the directory, and run code commands on the path to open up editor.
-func createPlaygroundDir(homeDir string, name string, genFilesList *os.File) error {
+// Following assumes that the homeDir is set up and the store has been passed in correctly.
+// Following creates the directory under the home directory. Need to make the directory, record it in the
+// index, and add a marker file in the directory (for ensuring do not delete wrong folder later). Then run
+// go mod init on the directory, materialize the starter template, and run code commands on the path to open
+// up editor.
+func createPlaygroundDir(homeDir string, name string, templateName string, st *store.Store) error {
 	if _, err := strconv.Atoi(name); err == nil {
 		return fmt.Errorf("could not create playground \"%s\"; name cannot be an integer", name)
 	}
@@ -92,7 +79,8 @@ func createPlaygroundDir(homeDir string, name string, genFilesList *os.File) err
 	dirPath := path.Join(homeDir, name)
 	// Make the new directory.
 	err := os.Mkdir(dirPath, 0777)
-	if err != nil && !errors.Is(err, os.ErrExist) {
+	alreadyExisted := errors.Is(err, os.ErrExist)
+	if err != nil && !alreadyExisted {
 		return err
 	}
 
@@ -100,31 +88,47 @@ func createPlaygroundDir(homeDir string, name string, genFilesList *os.File) err
 	if err := os.Chdir(dirPath); err != nil {
 		return err
 	}
-	// Now that the directory is made, we want to ensure it can be deleted later, even if the go mod init fails.
-	defer func() {
-		// Append the name to the file list.
-		if _, err := genFilesList.Seek(0, io.SeekEnd); err != nil {
-			panic(fmt.Sprintf("seek error: %s", err.Error()))
+	if templateName == "" {
+		templateName = templates.DefaultName
+	}
+	goModCMD := exec.Command("go", "mod", "init", "main")
+	goModCMD.Run() // Do not care about this error because it can happen on using old directory.
+
+	// Materialize the starter template's files, then apply any go.mod
+	// requirements its manifest asked for. This must succeed before we mark
+	// the directory as a real playground: otherwise a typo'd -t name leaves
+	// a half-created directory permanently recorded as valid, polluting ls
+	// and becoming the default target for run/-d.
+	manifest, err := templates.Materialize(homeDir, templateName, dirPath)
+	if err != nil {
+		if !alreadyExisted {
+			os.RemoveAll(dirPath)
 		}
-		genFilesList.Write([]byte(fmt.Sprintf("%s\n", dirPath))) // Do not care about error.
+		return err
+	}
+
+	// Now that the directory has real content, ensure it can be deleted
+	// later even if the steps below (go.mod requires, opening the editor) fail.
+	defer func() {
 		markerFileName := path.Join(dirPath, ".goplay_marker")
 		markerFile, _ := os.OpenFile(markerFileName, os.O_RDONLY|os.O_CREATE, 0777) // Consider default permissions.
 		markerFile.Close()
-	}()
-	goModCMD := exec.Command("go", "mod", "init", "main")
-	goModCMD.Run() // Do not care about this error because it can happen on using old directory.
 
-	// Adding main file.
-	file, err := os.OpenFile(path.Join(dirPath, "main.go"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0777)
-	if err == nil {
-		defer file.Close()
-		// Ok to write to the file.
-		if _, err := io.WriteString(file, programContents); err != nil {
-			return err
+		now := time.Now()
+		if err := st.Put(store.Entry{
+			Name:       name,
+			Path:       dirPath,
+			CreatedAt:  now,
+			Template:   templateName,
+			LastOpened: now,
+		}); err != nil {
+			fmt.Printf("Could not record playground %s in the index, raised error:\n\t\"%s\"\n", dirPath, err.Error())
 		}
-	} else {
-		if !errors.Is(err, os.ErrExist) {
-			return err // We expect an exists err, but no others.
+	}()
+	for _, require := range manifest.Require {
+		requireCMD := exec.Command("go", "mod", "edit", "-require="+require)
+		if err := requireCMD.Run(); err != nil {
+			return fmt.Errorf("could not add require %q from template %q: %w", require, templateName, err)
 		}
 	}
 	// The code cmd is not as simple as you would think. We want to call code on the directory && the file, so it opens up.
@@ -143,8 +147,12 @@ func createPlaygroundDir(homeDir string, name string, genFilesList *os.File) err
 	return nil
 }
 
-// Feed in the file for the list of generated files.
-func deletePlaygroundDirs(genFilesList *os.File, homeDir string, nameOrNumber string, deleteAll bool) {
+// deletePlaygroundDirs deletes playgrounds recorded in st, chosen by name or
+// by count (the nameOrNumber/deleteAll forms), or everything if deleteAll.
+// If archiveFlag is set, each playground is tarred into $GOPLAY_DIR/archive
+// before deletion. If dryRun is set, nothing is deleted or archived; the
+// paths that would have been deleted are just printed.
+func deletePlaygroundDirs(st *store.Store, homeDir string, nameOrNumber string, deleteAll bool, archiveFlag bool, dryRun bool) {
 	// Some checks happen if deleteAll is false.
 	toDelete := []string{} // File names, where file name is full path.
 	isNumber := false
@@ -168,23 +176,15 @@ func deletePlaygroundDirs(genFilesList *os.File, homeDir string, nameOrNumber st
 		toDelete = append(toDelete, path.Join(homeDir, nameOrNumber))
 	}
 
-	// Want to construct toDelete if we have not
-	_, err := genFilesList.Seek(0, io.SeekStart)
+	entries, err := st.List()
 	if err != nil {
-		panic(err)
-	}
-	lines := make([]string, 0)
-	scanner := bufio.NewScanner(genFilesList)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			lines = append(lines, line)
-		}
-	}
-	if err := scanner.Err(); err != nil {
-		fmt.Printf("scanning list of files raised error \"%s\"\n", err.Error())
+		fmt.Printf("encountered error listing the index: \"%s\"\n", err.Error())
 		return
 	}
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, entry.Path)
+	}
 
 	// If deleting all, set num to be number of lines.
 	if deleteAll {
@@ -199,46 +199,277 @@ func deletePlaygroundDirs(genFilesList *os.File, homeDir string, nameOrNumber st
 	if isNumber {
 		toDelete = append(toDelete, lines[len(lines)-num:]...)
 	}
-	// Now, we are ready to delete. If a delete succeeds, we want to remove that line from the new content of the .generated_dirs file.
-	toExclude := make(map[string]struct{}, len(toDelete))
+	deleteAndPrune(st, homeDir, toDelete, archiveFlag, dryRun)
+}
+
+// deleteAndPrune deletes each directory in toDelete (skipping any that fail,
+// e.g. missing marker file) and removes its entry from the index. If
+// archiveFlag is set, each directory is tarred into $GOPLAY_DIR/archive
+// first; if the archive fails, the directory is left alone rather than
+// deleted unarchived. If dryRun is set, it only prints what would be
+// deleted.
+func deleteAndPrune(st *store.Store, homeDir string, toDelete []string, archiveFlag bool, dryRun bool) {
+	if dryRun {
+		for _, filePath := range toDelete {
+			fmt.Printf("would delete %s\n", filePath)
+		}
+		return
+	}
+
+	archiveDir := path.Join(homeDir, "archive")
 	for _, filePath := range toDelete {
+		if archiveFlag {
+			archivePath, err := archive.Create(archiveDir, path.Base(filePath), filePath)
+			if err != nil {
+				fmt.Printf("Could not archive %s, got error:\n\t\"%s\"; skipping deletion\n", filePath, err.Error())
+				continue
+			}
+			fmt.Printf("archived %s to %s\n", filePath, archivePath)
+		}
 		// Try to delete.
 		if err := deletePlaygroundDir(filePath); err != nil {
-			// Failed, print error and do not add to toExclude.
+			// Failed, print error and leave its index entry alone.
 			fmt.Printf("Could not delete %s, got error:\n\t\"%s\"", filePath, err.Error())
+			continue
+		}
+		if err := st.Remove(filePath); err != nil {
+			fmt.Printf("deleted %s but could not remove it from the index: \"%s\"\n", filePath, err.Error())
+		}
+	}
+}
+
+// deletePlaygroundDirsByRetention deletes every playground matching the
+// given retention policy (older than olderThan and/or larger than
+// largerThan; zero values are treated as "unset").
+func deletePlaygroundDirsByRetention(st *store.Store, homeDir string, olderThan time.Duration, largerThan int64, archiveFlag bool, dryRun bool) {
+	infos, err := playground.List(st)
+	if err != nil {
+		fmt.Printf("encountered error listing playgrounds: \"%s\"\n", err.Error())
+		return
+	}
+
+	toDelete := make([]string, 0)
+	now := time.Now()
+	for _, info := range infos {
+		if !info.Exists {
+			continue
+		}
+		if olderThan > 0 && now.Sub(info.CreatedAt) < olderThan {
+			continue
+		}
+		if largerThan > 0 && info.SizeBytes < largerThan {
+			continue
+		}
+		toDelete = append(toDelete, info.Path)
+	}
+
+	deleteAndPrune(st, homeDir, toDelete, archiveFlag, dryRun)
+}
+
+// runLs implements 'goplay ls'. It prints every recorded playground's name,
+// path, creation time, size on disk, and whether it still exists, either as
+// a table or, with --json, as machine-readable JSON. --sort selects the
+// ordering: age, size, or name (the default).
+func runLs(st *store.Store, args []string) error {
+	fs := flag.NewFlagSet("ls", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "print machine-readable JSON instead of a table")
+	sortKey := fs.String("sort", "name", "sort by age, size, or name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	infos, err := playground.List(st)
+	if err != nil {
+		return err
+	}
+	playground.Sort(infos, playground.SortKey(*sortKey))
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPATH\tCREATED\tSIZE\tEXISTS")
+	for _, info := range infos {
+		created := "-"
+		if !info.CreatedAt.IsZero() {
+			created = info.CreatedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\n", info.Name, info.Path, created, info.SizeBytes, info.Exists)
+	}
+	return w.Flush()
+}
+
+// runTestAll implements 'goplay test --all'. It discovers every recorded,
+// still-existing playground and runs `go test ./...` in each, concurrently
+// up to -n workers, optionally restricted to one shard of -shards via
+// -shard, and prints a pass/fail summary.
+func runTestAll(st *store.Store, args []string) error {
+	fs := flag.NewFlagSet("test", flag.ExitOnError)
+	fs.Bool("all", false, "test every recorded playground")
+	n := fs.Int("n", runtime.NumCPU(), "number of playgrounds to test concurrently")
+	shard := fs.Int("shard", 0, "this worker's shard index")
+	shards := fs.Int("shards", 1, "total number of shards")
+	verbose := fs.Bool("v", false, "stream child output live (forces -n 1)")
+	timeout := fs.Duration("timeout", 0, "kill an individual playground's test run after this long")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	infos, err := playground.List(st)
+	if err != nil {
+		return err
+	}
+	dirs := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.Exists {
+			dirs = append(dirs, info.Path)
+		}
+	}
+
+	results := runner.TestAll(dirs, runner.BatchOptions{
+		Workers: *n,
+		Shard:   *shard,
+		Shards:  *shards,
+		Verbose: *verbose,
+		Timeout: *timeout,
+	})
+
+	passed, failed := 0, 0
+	for _, r := range results {
+		status := "ok"
+		switch {
+		case r.Killed:
+			status = "TIMEOUT"
+		case r.Err != nil:
+			status = "FAIL"
+		}
+		if r.Passed {
+			passed++
 		} else {
-			toExclude[filePath] = struct{}{}
+			failed++
 		}
+		if r.Err != nil {
+			fmt.Printf("%s\t%s\t%s\t%s\n", status, r.Dir, r.Elapsed.Round(time.Millisecond), r.Err.Error())
+		} else {
+			fmt.Printf("%s\t%s\t%s\n", status, r.Dir, r.Elapsed.Round(time.Millisecond))
+		}
+	}
+	fmt.Printf("\n%d passed, %d failed, %d total\n", passed, failed, len(results))
+	if failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runRestore implements 'goplay restore <archive>'. It extracts the archive
+// into a fresh playground directory (named after the archive, minus its
+// "-<timestamp>.tar.gz" suffix, falling back to a uuid if that name is
+// already taken by an existing directory or index entry), gives it a new
+// marker file, and records it in the index.
+func runRestore(homeDir string, st *store.Store, archivePath string) error {
+	base := path.Base(archivePath)
+	base = strings.TrimSuffix(base, ".tar.gz")
+	if i := strings.LastIndex(base, "-"); i >= 0 {
+		base = base[:i]
+	}
+	name := base
+	if _, err := strconv.Atoi(name); err == nil || name == "" {
+		name = uuid.New().String()
+	}
+
+	dirPath := path.Join(homeDir, name)
+	// If a playground already lives at dirPath (either a directory already
+	// exists there, or the index still has an entry for it, e.g. the name was
+	// reused by an unrelated playground since this one was archived), restore
+	// under a fresh name instead of silently merging into whatever is there.
+	if _, err := os.Stat(dirPath); err == nil || entryExists(st, dirPath) {
+		name = uuid.New().String()
+		dirPath = path.Join(homeDir, name)
+	}
+	if err := os.MkdirAll(dirPath, 0777); err != nil {
+		return err
+	}
+	if err := archive.Restore(archivePath, dirPath); err != nil {
+		return err
+	}
+
+	markerFile, err := os.OpenFile(path.Join(dirPath, ".goplay_marker"), os.O_RDONLY|os.O_CREATE, 0777)
+	if err != nil {
+		return err
 	}
-	// Now, want to create the new lines that will be written to the file
-	newLines := make([]string, 0, len(lines))
-	// Add the lines from lines in order, as long as they are not supposed to be excluded.
-	for _, line := range lines {
-		if _, found := toExclude[line]; !found {
-			newLines = append(newLines, line) // Do not want to exclude
+	markerFile.Close()
+
+	now := time.Now()
+	return st.Put(store.Entry{
+		Name:       name,
+		Path:       dirPath,
+		CreatedAt:  now,
+		LastOpened: now,
+	})
+}
+
+// splitFlagArgs separates args into flag tokens and positional tokens, so
+// that flag.FlagSet.Parse (which stops at the first non-flag token) doesn't
+// silently drop flags that happen to come after a positional argument, e.g.
+// 'goplay -d myplayground --archive'. valueFlags names the flags that
+// consume a following token as their value when given as '-name value'
+// rather than '-name=value'; every other '-'-prefixed token is treated as a
+// standalone (boolean) flag.
+func splitFlagArgs(args []string, valueFlags map[string]bool) (flagArgs, positional []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+		flagArgs = append(flagArgs, arg)
+		name := strings.TrimLeft(arg, "-")
+		if eq := strings.IndexByte(name, '='); eq >= 0 {
+			continue // '-name=value' form; the value is already part of arg.
 		}
+		if valueFlags[name] && i+1 < len(args) {
+			i++
+			flagArgs = append(flagArgs, args[i])
+		}
+	}
+	return flagArgs, positional
+}
+
+// entryExists reports whether st already has an index entry for dirPath.
+func entryExists(st *store.Store, dirPath string) bool {
+	entries, err := st.List()
+	if err != nil {
+		return false
 	}
-	newContent := strings.Join(newLines, "\n")
-	if newContent != "" {
-		newContent += "\n"
+	for _, entry := range entries {
+		if entry.Path == dirPath {
+			return true
+		}
 	}
+	return false
+}
 
-	if _, err := genFilesList.Seek(0, io.SeekStart); err != nil {
-		fmt.Printf("encountered seek error: \"%s\"\n", err.Error())
-		return
+// resolvePlaygroundDir returns the playground directory for the given name.
+// If name is empty, it defaults to the most recently created entry in st.
+func resolvePlaygroundDir(homeDir string, st *store.Store, name string) (string, error) {
+	if name != "" {
+		return path.Join(homeDir, name), nil
 	}
-	if _, err := genFilesList.WriteString(newContent); err != nil {
-		fmt.Printf("encountered write error: \"%s\"\n", err.Error())
-		return
+
+	entry, ok, err := st.MostRecent()
+	if err != nil {
+		return "", err
 	}
-	// Truncate off the end.
-	if err := genFilesList.Truncate(int64(len(newContent))); err != nil {
-		fmt.Printf("encountered truncate error: \"%s\"\n", err.Error())
-		return
+	if !ok {
+		return "", fmt.Errorf("no playgrounds found; create one first with 'goplay'")
 	}
+	return entry.Path, nil
 }
 
-// Helper called for deleting directories. Only deletes directory if marker file found. If not, returns an error. Also, does not touch list file, that is done elsewhere.
+// Helper called for deleting directories. Only deletes directory if marker file found. If not, returns an error. Also, does not touch the index, that is done elsewhere.
 func deletePlaygroundDir(dir string) error {
 	// First check that directory exists.
 	if _, err := os.Stat(dir); err != nil {
@@ -263,25 +494,111 @@ func deletePlaygroundDir(dir string) error {
 
 func main() {
 	// Need to sort through args. First, if too many just error out.
-	if len(os.Args) > 3 {
-		// max is goplay -d name (3)
+	if len(os.Args) > 12 {
+		// max is goplay test --all -n 4 -shard 1 -shards 4 -v --timeout 30s (12)
 		fmt.Println("Too many arguments for goplay. Call 'goplay --help' for more information.")
 		os.Exit(1) // Error.
 	}
 	// Next, handle in orders of complexity. First, help
 	if len(os.Args) >= 2 && os.Args[1] == "--help" {
-		fmt.Println(helpMessage)
+		fmt.Printf(helpMessage+"\n", strings.Join(templates.Names(), ", "))
 		return
 	}
 
 	// From here on, we need setup.
 	homeDir := getHomeDir()
-	genFilesList, err := setupHomeDir(homeDir)
-	if err != nil {
+	if err := os.MkdirAll(homeDir, 0777); err != nil {
 		fmt.Printf("Failed to set up home directory, raised error:\n\t\"%s\"\n", err.Error())
 		os.Exit(1)
 	}
-	defer genFilesList.Close()
+	st, err := store.Open(homeDir)
+	if err != nil {
+		fmt.Printf("Failed to open the playground index, raised error:\n\t\"%s\"\n", err.Error())
+		os.Exit(1)
+	}
+
+	// 'test --all': batch-test every recorded playground, not just one.
+	if len(os.Args) >= 3 && os.Args[1] == "test" {
+		for _, arg := range os.Args[2:] {
+			if arg == "--all" || arg == "-all" {
+				if err := runTestAll(st, os.Args[2:]); err != nil {
+					fmt.Printf("Could not run batch tests, raised error:\n\t\"%s\"\n", err.Error())
+					os.Exit(1)
+				}
+				return
+			}
+		}
+	}
+
+	// Next, the toolchain passthrough subcommands: run, build, test.
+	runnerCmds := map[string]func(string, []string) (int, error){
+		"run":   runner.Run,
+		"build": runner.Build,
+		"test":  runner.Test,
+	}
+	if len(os.Args) >= 2 {
+		if run, ok := runnerCmds[os.Args[1]]; ok {
+			name := ""
+			if len(os.Args) > 2 {
+				name = os.Args[2]
+			}
+			dir, err := resolvePlaygroundDir(homeDir, st, name)
+			if err != nil {
+				fmt.Printf("Could not resolve playground, raised error:\n\t\"%s\"\n", err.Error())
+				os.Exit(1)
+			}
+			st.Touch(dir) // Best-effort; does not affect the command's outcome.
+			code, err := run(dir, nil)
+			if err != nil {
+				fmt.Printf("Could not run 'go %s' in %s, raised error:\n\t\"%s\"\n", os.Args[1], dir, err.Error())
+				os.Exit(1)
+			}
+			os.Exit(code)
+		}
+	}
+
+	// restore <archive>: reconstitute an archived playground as a new one.
+	if len(os.Args) >= 2 && os.Args[1] == "restore" {
+		if len(os.Args) < 3 {
+			fmt.Println("'restore' requires an archive path. Call 'goplay --help' for more information.")
+			os.Exit(1)
+		}
+		if err := runRestore(homeDir, st, os.Args[2]); err != nil {
+			fmt.Printf("Could not restore archive, raised error:\n\t\"%s\"\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	// ls: list recorded playgrounds with metadata.
+	if len(os.Args) >= 2 && os.Args[1] == "ls" {
+		if err := runLs(st, os.Args[2:]); err != nil {
+			fmt.Printf("Could not list playgrounds, raised error:\n\t\"%s\"\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	// -t template [name]: create a resource from a named starter template.
+	if len(os.Args) >= 2 && os.Args[1] == "-t" {
+		if len(os.Args) < 3 {
+			fmt.Println("'-t' requires a template name. Call 'goplay --help' for more information.")
+			os.Exit(1)
+		}
+		templateName := os.Args[2]
+		name := ""
+		if len(os.Args) > 3 {
+			name = os.Args[3]
+		}
+
+		err := createPlaygroundDir(homeDir, name, templateName, st)
+		if err != nil {
+			fmt.Printf("Could not create the playground directory, raised error:\n\t\"%s\"\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Next simplest, creating a resource. This happens if only 1 arg (command name), or if os.Args[1] is not -d or -D or --help (though we already checked --help).
 	if len(os.Args) <= 1 || (os.Args[1] != "-d" && os.Args[1] != "-D") {
 		name := ""
@@ -289,7 +606,7 @@ func main() {
 			name = os.Args[1]
 		}
 
-		err := createPlaygroundDir(homeDir, name, genFilesList)
+		err := createPlaygroundDir(homeDir, name, "", st)
 		if err != nil {
 			fmt.Printf("Could not create the playground directory, raised error:\n\t\"%s\"\n", err.Error())
 			os.Exit(1)
@@ -301,24 +618,76 @@ func main() {
 	// Next simplest, deleting some entries.
 	// We know there are at least 2 args, because we already checked if there were <= 1 (and returned).
 	if os.Args[1] == "-d" {
+		fs := flag.NewFlagSet("-d", flag.ExitOnError)
+		olderThanStr := fs.String("older-than", "", "delete playgrounds older than this age, e.g. 30d")
+		largerThanStr := fs.String("larger-than", "", "delete playgrounds larger than this size, e.g. 50M")
+		archiveFlag := fs.Bool("archive", false, "tar.gz each playground into $GOPLAY_DIR/archive before deleting it")
+		dryRun := fs.Bool("dry-run", false, "print what would be deleted without touching disk")
+		// Flags may come before or after the name/count positional arg (e.g.
+		// 'goplay -d myplayground --archive'); pull them out first so
+		// fs.Parse, which stops at the first non-flag token, still sees all
+		// of them regardless of order.
+		flagArgs, rest := splitFlagArgs(os.Args[2:], map[string]bool{"older-than": true, "larger-than": true})
+		if err := fs.Parse(flagArgs); err != nil {
+			os.Exit(1)
+		}
+		if len(rest) > 1 {
+			fmt.Printf("'-d' takes at most one name or count argument, got %v\n", rest)
+			os.Exit(1)
+		}
+
+		// A retention policy (--older-than/--larger-than) replaces the
+		// name-or-count form entirely.
+		if *olderThanStr != "" || *largerThanStr != "" {
+			var olderThan time.Duration
+			if *olderThanStr != "" {
+				d, err := playground.ParseAge(*olderThanStr)
+				if err != nil {
+					fmt.Printf("Could not parse --older-than: \"%s\"\n", err.Error())
+					os.Exit(1)
+				}
+				olderThan = d
+			}
+			var largerThan int64
+			if *largerThanStr != "" {
+				n, err := playground.ParseSize(*largerThanStr)
+				if err != nil {
+					fmt.Printf("Could not parse --larger-than: \"%s\"\n", err.Error())
+					os.Exit(1)
+				}
+				largerThan = n
+			}
+			deletePlaygroundDirsByRetention(st, homeDir, olderThan, largerThan, *archiveFlag, *dryRun)
+			return
+		}
+
 		nameOrNumber := "1" // Defaults to deleting most recent.
-		if len(os.Args) > 2 {
-			nameOrNumber = os.Args[2]
+		if len(rest) > 0 {
+			nameOrNumber = rest[0]
 		}
-		deletePlaygroundDirs(genFilesList, homeDir, nameOrNumber, false)
+		deletePlaygroundDirs(st, homeDir, nameOrNumber, false, *archiveFlag, *dryRun)
 		return
 	}
 
 	// Finally, deleting all entries. We know there are at least 2 args. If next one is -D flag, we delete everything. Require confirmation
 	if os.Args[1] == "-D" {
-		input := ""
-		fmt.Print("Delete all playgrounds? Enter 'y' to confirm: ")
-		fmt.Scanln(&input)
-		if input != "y" {
-			fmt.Println("Aborting deletion.")
-			return
+		fs := flag.NewFlagSet("-D", flag.ExitOnError)
+		archiveFlag := fs.Bool("archive", false, "tar.gz each playground into $GOPLAY_DIR/archive before deleting it")
+		dryRun := fs.Bool("dry-run", false, "print what would be deleted without touching disk")
+		if err := fs.Parse(os.Args[2:]); err != nil {
+			os.Exit(1)
+		}
+
+		if !*dryRun {
+			input := ""
+			fmt.Print("Delete all playgrounds? Enter 'y' to confirm: ")
+			fmt.Scanln(&input)
+			if input != "y" {
+				fmt.Println("Aborting deletion.")
+				return
+			}
 		}
-		deletePlaygroundDirs(genFilesList, homeDir, "", true)
+		deletePlaygroundDirs(st, homeDir, "", true, *archiveFlag, *dryRun)
 	}
 
 	// NOTE: by how we handled cases, it is not possible to get here.
@@ -0,0 +1,161 @@
+// Package archive snapshots a playground directory to a tar.gz file before
+// it is deleted, and restores one back into a new playground.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// excluded reports whether rel (a path relative to the playground root)
+// should be left out of the archive: the marker file itself, and any
+// vendor or build-cache directories.
+func excluded(rel string) bool {
+	if rel == ".goplay_marker" {
+		return true
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part == "vendor" || part == ".cache" {
+			return true
+		}
+	}
+	return false
+}
+
+// Create tars and gzips srcDir into archiveDir/<name>-<unix timestamp>.tar.gz
+// and returns the archive's path.
+func Create(archiveDir, name, srcDir string) (string, error) {
+	if err := os.MkdirAll(archiveDir, 0777); err != nil {
+		return "", err
+	}
+	archivePath := filepath.Join(archiveDir, fmt.Sprintf("%s-%d.tar.gz", name, time.Now().Unix()))
+
+	out, err := os.OpenFile(archivePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	walkErr := filepath.Walk(srcDir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if excluded(rel) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if closeErr := tw.Close(); closeErr != nil && walkErr == nil {
+		walkErr = closeErr
+	}
+	if closeErr := gz.Close(); closeErr != nil && walkErr == nil {
+		walkErr = closeErr
+	}
+	if walkErr != nil {
+		os.Remove(archivePath) // Don't leave a half-written archive behind.
+		return "", walkErr
+	}
+	return archivePath, nil
+}
+
+// safeJoin joins destDir with the tar entry name rel, rejecting any entry
+// whose ".." components would escape destDir, as produced by a malicious or
+// corrupted archive. An absolute entry name is joined under destDir rather
+// than rejected. The check is lexical; symlinks on disk are not examined.
+func safeJoin(destDir, rel string) (string, error) {
+	target := filepath.Join(destDir, filepath.FromSlash(rel))
+	destDir = filepath.Clean(destDir)
+	if target != destDir && !strings.HasPrefix(target, destDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %q escapes destination directory", rel)
+	}
+	return target, nil
+}
+
+// Restore extracts the tar.gz at archivePath into destDir, which must
+// already exist.
+func Restore(archivePath, destDir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := safeJoin(destDir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			out.Close()
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateRestoreRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "main.go"), []byte("package main\n"), 0666); err != nil {
+		t.Fatalf("write main.go: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, ".goplay_marker"), nil, 0666); err != nil {
+		t.Fatalf("write marker: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(srcDir, "vendor", "dep"), 0777); err != nil {
+		t.Fatalf("mkdir vendor: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "vendor", "dep", "dep.go"), []byte("package dep\n"), 0666); err != nil {
+		t.Fatalf("write vendor file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(srcDir, "pkg"), 0777); err != nil {
+		t.Fatalf("mkdir pkg: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "pkg", "pkg.go"), []byte("package pkg\n"), 0666); err != nil {
+		t.Fatalf("write pkg file: %v", err)
+	}
+
+	archiveDir := t.TempDir()
+	archivePath, err := Create(archiveDir, "myplayground", srcDir)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+
+	destDir := t.TempDir()
+	if err := Restore(archivePath, destDir); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(destDir, ".goplay_marker")); !os.IsNotExist(err) {
+		t.Fatalf(".goplay_marker should have been excluded from the archive, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "vendor")); !os.IsNotExist(err) {
+		t.Fatalf("vendor/ should have been excluded from the archive, stat err = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(destDir, "main.go"))
+	if err != nil {
+		t.Fatalf("restored main.go: %v", err)
+	}
+	if string(got) != "package main\n" {
+		t.Fatalf("restored main.go = %q, want %q", got, "package main\n")
+	}
+	got, err = os.ReadFile(filepath.Join(destDir, "pkg", "pkg.go"))
+	if err != nil {
+		t.Fatalf("restored pkg/pkg.go: %v", err)
+	}
+	if string(got) != "package pkg\n" {
+		t.Fatalf("restored pkg/pkg.go = %q, want %q", got, "package pkg\n")
+	}
+}
+
+// writeMaliciousArchive builds a tar.gz containing a single regular-file entry
+// named entryName, e.g. a relative "../" traversal or an absolute path.
+func writeMaliciousArchive(t *testing.T, path, entryName, contents string) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{
+		Name: entryName,
+		Mode: 0666,
+		Size: int64(len(contents)),
+	}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	if _, err := tw.Write([]byte(contents)); err != nil {
+		t.Fatalf("write body: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip writer: %v", err)
+	}
+}
+
+func TestRestoreRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "evil.tar.gz")
+	outsideDir := filepath.Join(dir, "outside")
+	if err := os.MkdirAll(outsideDir, 0777); err != nil {
+		t.Fatalf("mkdir outside: %v", err)
+	}
+	writeMaliciousArchive(t, archivePath, "../outside/pwned.txt", "pwned")
+
+	destDir := filepath.Join(dir, "dest")
+	if err := os.MkdirAll(destDir, 0777); err != nil {
+		t.Fatalf("mkdir dest: %v", err)
+	}
+
+	if err := Restore(archivePath, destDir); err == nil {
+		t.Fatalf("Restore did not reject a path-traversal entry")
+	}
+	if _, err := os.Stat(filepath.Join(outsideDir, "pwned.txt")); !os.IsNotExist(err) {
+		t.Fatalf("Restore wrote outside destDir: stat err = %v", err)
+	}
+}
+
+func TestRestoreContainsAbsolutePathEntry(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "evil.tar.gz")
+	writeMaliciousArchive(t, archivePath, "/etc/pwned.txt", "pwned")
+
+	destDir := filepath.Join(dir, "dest")
+	if err := os.MkdirAll(destDir, 0777); err != nil {
+		t.Fatalf("mkdir dest: %v", err)
+	}
+
+	if err := Restore(archivePath, destDir); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := os.Stat("/etc/pwned.txt"); !os.IsNotExist(err) {
+		t.Fatalf("Restore wrote to an absolute path outside destDir: stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, "etc", "pwned.txt")); err != nil {
+		t.Fatalf("expected the absolute-path entry to land inside destDir: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Package playground collects metadata about the playground directories
+// GoPlay has created, combining each entry's recorded metadata (from
+// internal/store) with what's actually on disk.
+package playground
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"goplay/internal/store"
+)
+
+// Info describes a single playground directory.
+type Info struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	CreatedAt  time.Time `json:"created_at"`
+	Template   string    `json:"template,omitempty"`
+	LastOpened time.Time `json:"last_opened,omitempty"`
+	SizeBytes  int64     `json:"size_bytes"`
+	Exists     bool      `json:"exists"`
+}
+
+// List returns Info for every entry in st, in the order they were created.
+func List(st *store.Store) ([]Info, error) {
+	entries, err := st.List()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, stat(entry))
+	}
+	return infos, nil
+}
+
+// stat builds an Info from a store entry, tolerating a nonexistent directory
+// (Exists is simply set to false, and SizeBytes left at zero).
+func stat(entry store.Entry) Info {
+	info := Info{
+		Name:       entry.Name,
+		Path:       entry.Path,
+		CreatedAt:  entry.CreatedAt,
+		Template:   entry.Template,
+		LastOpened: entry.LastOpened,
+	}
+
+	if _, err := os.Stat(filepath.Join(entry.Path, ".goplay_marker")); err != nil {
+		return info
+	}
+	info.Exists = true
+	info.SizeBytes = dirSize(entry.Path)
+	return info
+}
+
+// dirSize sums the apparent size of every regular file under dirPath.
+func dirSize(dirPath string) int64 {
+	var size int64
+	filepath.Walk(dirPath, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Best-effort; skip files we can't stat.
+		}
+		if !fi.IsDir() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// SortKey names a field infos can be sorted by.
+type SortKey string
+
+const (
+	SortAge  SortKey = "age"
+	SortSize SortKey = "size"
+	SortName SortKey = "name"
+)
+
+// Sort orders infos in place by key. Ties fall back to name for determinism.
+func Sort(infos []Info, key SortKey) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		switch key {
+		case SortAge:
+			if !infos[i].CreatedAt.Equal(infos[j].CreatedAt) {
+				return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+			}
+		case SortSize:
+			if infos[i].SizeBytes != infos[j].SizeBytes {
+				return infos[i].SizeBytes < infos[j].SizeBytes
+			}
+		}
+		return infos[i].Name < infos[j].Name
+	})
+}
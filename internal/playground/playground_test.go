@@ -0,0 +1,118 @@
+package playground
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goplay/internal/store"
+)
+
+func TestSort(t *testing.T) {
+	infos := []Info{
+		{Name: "b", CreatedAt: time.Unix(2, 0), SizeBytes: 10},
+		{Name: "a", CreatedAt: time.Unix(3, 0), SizeBytes: 30},
+		{Name: "c", CreatedAt: time.Unix(1, 0), SizeBytes: 20},
+	}
+
+	Sort(infos, SortName)
+	wantNames := []string{"a", "b", "c"}
+	for i, info := range infos {
+		if info.Name != wantNames[i] {
+			t.Fatalf("Sort(name) = %v, want order %v", namesOf(infos), wantNames)
+		}
+	}
+
+	Sort(infos, SortAge)
+	wantNames = []string{"c", "b", "a"}
+	for i, info := range infos {
+		if info.Name != wantNames[i] {
+			t.Fatalf("Sort(age) = %v, want order %v", namesOf(infos), wantNames)
+		}
+	}
+
+	Sort(infos, SortSize)
+	wantNames = []string{"b", "c", "a"}
+	for i, info := range infos {
+		if info.Name != wantNames[i] {
+			t.Fatalf("Sort(size) = %v, want order %v", namesOf(infos), wantNames)
+		}
+	}
+}
+
+func TestSortTiesFallBackToName(t *testing.T) {
+	infos := []Info{
+		{Name: "z", CreatedAt: time.Unix(1, 0)},
+		{Name: "a", CreatedAt: time.Unix(1, 0)},
+	}
+	Sort(infos, SortAge)
+	if infos[0].Name != "a" || infos[1].Name != "z" {
+		t.Fatalf("Sort(age) with tied ages = %v, want name-ordered", namesOf(infos))
+	}
+}
+
+func namesOf(infos []Info) []string {
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
+	return names
+}
+
+func TestListReflectsDiskState(t *testing.T) {
+	goplayDir := t.TempDir()
+	st, err := store.Open(goplayDir)
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+
+	existingDir := filepath.Join(goplayDir, "existing")
+	if err := os.MkdirAll(existingDir, 0777); err != nil {
+		t.Fatalf("mkdir existing: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(existingDir, ".goplay_marker"), nil, 0666); err != nil {
+		t.Fatalf("write marker: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(existingDir, "main.go"), []byte("package main\n"), 0666); err != nil {
+		t.Fatalf("write main.go: %v", err)
+	}
+
+	goneDir := filepath.Join(goplayDir, "gone")
+
+	if err := st.Put(store.Entry{Name: "existing", Path: existingDir, CreatedAt: time.Unix(1, 0)}); err != nil {
+		t.Fatalf("Put(existing): %v", err)
+	}
+	if err := st.Put(store.Entry{Name: "gone", Path: goneDir, CreatedAt: time.Unix(2, 0)}); err != nil {
+		t.Fatalf("Put(gone): %v", err)
+	}
+
+	infos, err := List(st)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("List returned %d infos, want 2", len(infos))
+	}
+
+	byName := make(map[string]Info)
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+
+	existing := byName["existing"]
+	if !existing.Exists {
+		t.Fatalf("existing playground Exists = false, want true")
+	}
+	if existing.SizeBytes <= 0 {
+		t.Fatalf("existing playground SizeBytes = %d, want > 0", existing.SizeBytes)
+	}
+
+	gone := byName["gone"]
+	if gone.Exists {
+		t.Fatalf("deleted playground Exists = true, want false")
+	}
+	if gone.SizeBytes != 0 {
+		t.Fatalf("deleted playground SizeBytes = %d, want 0", gone.SizeBytes)
+	}
+}
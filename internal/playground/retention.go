@@ -0,0 +1,44 @@
+package playground
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseAge parses a retention age like "30d", "12h" or "45m" into a
+// time.Duration. Unlike time.ParseDuration, it understands a "d" (day)
+// suffix, since retention policies are usually expressed in days.
+func ParseAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q: %w", s, err)
+		}
+		return time.Duration(days * 24 * float64(time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ParseSize parses a size like "50M", "2G" or "512K" into a byte count.
+// Suffixes are binary (1024-based): K, M, G. A bare number is bytes.
+func ParseSize(s string) (int64, error) {
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1 << 10
+		s = strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1 << 20
+		s = strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		multiplier = 1 << 30
+		s = strings.TrimSuffix(s, "G")
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	return int64(n * float64(multiplier)), nil
+}
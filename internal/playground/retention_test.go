@@ -0,0 +1,69 @@
+package playground
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"0d", 0, false},
+		{"12h", 12 * time.Hour, false},
+		{"45m", 45 * time.Minute, false},
+		{"not-a-duration", 0, true},
+		{"d", 0, true},
+	}
+	for _, c := range cases {
+		got, err := ParseAge(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseAge(%q) = %v, want error", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAge(%q) unexpected error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseAge(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"1K", 1 << 10, false},
+		{"50M", 50 << 20, false},
+		{"2G", 2 << 30, false},
+		{"0.5M", (1 << 20) / 2, false},
+		{"not-a-size", 0, true},
+	}
+	for _, c := range cases {
+		got, err := ParseSize(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseSize(%q) = %v, want error", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSize(%q) unexpected error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseSize(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
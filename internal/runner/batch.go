@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures TestAll.
+type BatchOptions struct {
+	// Workers caps the number of playgrounds tested concurrently. Zero
+	// means runtime.NumCPU().
+	Workers int
+	// Shard and Shards partition the playground list by hashing each
+	// playground's path with fnv and keeping only Shard == hash % Shards.
+	// Shards <= 1 means "no sharding, run everything".
+	Shard  int
+	Shards int
+	// Verbose streams each child's stdout/stderr live instead of buffering
+	// it until the test completes, and forces Workers to 1 so output from
+	// different playgrounds doesn't interleave.
+	Verbose bool
+	// Timeout, if positive, kills an individual playground's `go test` if
+	// it runs longer than this.
+	Timeout time.Duration
+}
+
+// Result is the outcome of testing a single playground.
+type Result struct {
+	Dir     string
+	Passed  bool
+	Killed  bool
+	Err     error
+	Elapsed time.Duration
+}
+
+// shardOf returns which shard, in the range [0, shards), a playground path falls into.
+func shardOf(dir string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(dir))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// TestAll runs `go test ./...` in every playground in dirs, up to
+// opts.Workers at a time, and returns one Result per selected playground (in
+// no particular order).
+func TestAll(dirs []string, opts BatchOptions) []Result {
+	if opts.Verbose {
+		opts.Workers = 1
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	selected := dirs
+	if opts.Shards > 1 {
+		selected = selected[:0:0]
+		for _, dir := range dirs {
+			if shardOf(dir, opts.Shards) == opts.Shard {
+				selected = append(selected, dir)
+			}
+		}
+	}
+
+	results := make([]Result, len(selected))
+	sem := make(chan struct{}, opts.Workers)
+	var wg sync.WaitGroup
+	for i, dir := range selected {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, dir string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = testOne(dir, opts)
+		}(i, dir)
+	}
+	wg.Wait()
+	return results
+}
+
+// testOne runs `go test ./...` in a single playground directory.
+func testOne(dir string, opts BatchOptions) Result {
+	start := time.Now()
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test", "./...")
+	cmd.Dir = dir
+	var buf bytes.Buffer
+	if opts.Verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	} else {
+		cmd.Stdout = &buf
+		cmd.Stderr = &buf
+	}
+
+	err := cmd.Run()
+	elapsed := time.Since(start)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return Result{Dir: dir, Killed: true, Err: fmt.Errorf("killed after exceeding timeout of %s", opts.Timeout), Elapsed: elapsed}
+	}
+	if err != nil {
+		if !opts.Verbose {
+			os.Stderr.Write(buf.Bytes())
+		}
+		return Result{Dir: dir, Err: err, Elapsed: elapsed}
+	}
+	return Result{Dir: dir, Passed: true, Elapsed: elapsed}
+}
@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestShardOfIsStableAndInRange(t *testing.T) {
+	dirs := []string{"/home/a", "/home/b", "/home/c", "/home/d", "/home/e"}
+	const shards = 4
+	for _, dir := range dirs {
+		shard := shardOf(dir, shards)
+		if shard < 0 || shard >= shards {
+			t.Fatalf("shardOf(%q, %d) = %d, want in [0, %d)", dir, shards, shard, shards)
+		}
+		if again := shardOf(dir, shards); again != shard {
+			t.Fatalf("shardOf(%q, %d) not stable: %d then %d", dir, shards, shard, again)
+		}
+	}
+}
+
+func TestShardOfPartitionsDisjointly(t *testing.T) {
+	dirs := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		dirs = append(dirs, filepath.Join("/home", string(rune('a'+i))))
+	}
+
+	const shards = 4
+	seen := make(map[string]int)
+	for shard := 0; shard < shards; shard++ {
+		for _, dir := range dirs {
+			if shardOf(dir, shards) == shard {
+				seen[dir]++
+			}
+		}
+	}
+	for dir, count := range seen {
+		if count != 1 {
+			t.Fatalf("dir %q assigned to %d shards, want exactly 1", dir, count)
+		}
+	}
+	if len(seen) != len(dirs) {
+		t.Fatalf("sharding covered %d of %d dirs", len(seen), len(dirs))
+	}
+}
+
+// writeTestModule writes a minimal module with a single passing or failing
+// test into dir.
+func writeTestModule(t *testing.T, dir string, sleep time.Duration, fail bool) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module sample\n\ngo 1.21\n"), 0666); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	body := "func TestIt(t *testing.T) {"
+	if sleep > 0 {
+		body += "\n\ttime.Sleep(" + strconv.FormatInt(int64(sleep), 10) + ")"
+	}
+	if fail {
+		body += "\n\tt.Fatal(\"boom\")"
+	}
+	body += "\n}\n"
+	imports := `"testing"`
+	if sleep > 0 {
+		imports += "\n\t\"time\""
+	}
+	src := "package sample\n\nimport (\n\t" + imports + "\n)\n\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "sample_test.go"), []byte(src), 0666); err != nil {
+		t.Fatalf("write sample_test.go: %v", err)
+	}
+}
+
+func TestTestAllShardingSelectsOnlyMatchingShard(t *testing.T) {
+	dirA := t.TempDir()
+	writeTestModule(t, dirA, 0, false)
+	dirB := t.TempDir()
+	writeTestModule(t, dirB, 0, false)
+	dirs := []string{dirA, dirB}
+
+	const shards = 2
+	var total []Result
+	for shard := 0; shard < shards; shard++ {
+		results := TestAll(dirs, BatchOptions{Shard: shard, Shards: shards})
+		for _, dir := range dirs {
+			if shardOf(dir, shards) != shard {
+				for _, r := range results {
+					if r.Dir == dir {
+						t.Fatalf("shard %d tested %q, which belongs to a different shard", shard, dir)
+					}
+				}
+			}
+		}
+		total = append(total, results...)
+	}
+	if len(total) != len(dirs) {
+		t.Fatalf("sharded runs tested %d playgrounds total, want %d", len(total), len(dirs))
+	}
+}
+
+func TestTestAllReportsPassAndFail(t *testing.T) {
+	passDir := t.TempDir()
+	writeTestModule(t, passDir, 0, false)
+	failDir := t.TempDir()
+	writeTestModule(t, failDir, 0, true)
+
+	results := TestAll([]string{passDir, failDir}, BatchOptions{})
+	if len(results) != 2 {
+		t.Fatalf("TestAll returned %d results, want 2", len(results))
+	}
+
+	byDir := make(map[string]Result)
+	for _, r := range results {
+		byDir[r.Dir] = r
+	}
+	if !byDir[passDir].Passed {
+		t.Fatalf("expected %q to pass: %+v", passDir, byDir[passDir])
+	}
+	if byDir[failDir].Passed || byDir[failDir].Err == nil {
+		t.Fatalf("expected %q to fail with an error: %+v", failDir, byDir[failDir])
+	}
+}
+
+func TestTestAllTimeoutKillsSlowPlaygrounds(t *testing.T) {
+	dir := t.TempDir()
+	writeTestModule(t, dir, 500*time.Millisecond, false)
+
+	results := TestAll([]string{dir}, BatchOptions{Timeout: 50 * time.Millisecond})
+	if len(results) != 1 {
+		t.Fatalf("TestAll returned %d results, want 1", len(results))
+	}
+	r := results[0]
+	if !r.Killed {
+		t.Fatalf("expected the slow playground to be Killed: %+v", r)
+	}
+	if r.Err == nil {
+		t.Fatalf("expected a timeout reason in Err, got nil")
+	}
+}
+
+func TestTestAllVerboseRunsEveryPlayground(t *testing.T) {
+	dirA := t.TempDir()
+	writeTestModule(t, dirA, 0, false)
+	dirB := t.TempDir()
+	writeTestModule(t, dirB, 0, false)
+
+	results := TestAll([]string{dirA, dirB}, BatchOptions{Workers: 8, Verbose: true})
+	if len(results) != 2 {
+		t.Fatalf("TestAll returned %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if !r.Passed {
+			t.Fatalf("expected %q to pass: %+v", r.Dir, r)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Package runner wraps the go toolchain commands (run/build/test) that
+// GoPlay invokes against a playground directory.
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// Command runs `go <goSubcommand> <args...>` with its working directory set
+// to dir, streaming the child's stdin/stdout/stderr through to this process.
+// It returns the child's exit code; a non-zero code from the child is not
+// treated as an error, only a failure to start or wait on the process is.
+func Command(dir string, goSubcommand string, args []string) (int, error) {
+	cmdArgs := append([]string{goSubcommand}, args...)
+	cmd := exec.Command("go", cmdArgs...)
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 1, err
+}
+
+// Run invokes `go run .` inside dir.
+func Run(dir string, args []string) (int, error) {
+	return Command(dir, "run", append([]string{"."}, args...))
+}
+
+// Build invokes `go build ./...` inside dir.
+func Build(dir string, args []string) (int, error) {
+	return Command(dir, "build", append([]string{"./..."}, args...))
+}
+
+// Test invokes `go test ./...` inside dir.
+func Test(dir string, args []string) (int, error) {
+	return Command(dir, "test", append([]string{"./..."}, args...))
+}
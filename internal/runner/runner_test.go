@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeModule writes a minimal buildable module (go.mod + main.go) into dir.
+func writeModule(t *testing.T, dir, mainSrc string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module sample\n\ngo 1.21\n"), 0666); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(mainSrc), 0666); err != nil {
+		t.Fatalf("write main.go: %v", err)
+	}
+}
+
+func TestCommandPropagatesExitCode(t *testing.T) {
+	// `go` itself exits 2 on an unrecognized subcommand, giving a
+	// deterministic non-zero exit code to check Command maps correctly,
+	// without depending on how a particular go subcommand forwards a child
+	// program's own exit code (e.g. `go run` always exits 1 regardless).
+	code, err := Command(t.TempDir(), "bogus-subcommand", nil)
+	if err != nil {
+		t.Fatalf("Command: %v", err)
+	}
+	if code != 2 {
+		t.Fatalf("Command exit code = %d, want 2", code)
+	}
+}
+
+func TestCommandSuccess(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, `package main
+
+func main() {}
+`)
+
+	code, err := Build(dir, nil)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if code != 0 {
+		t.Fatalf("Build exit code = %d, want 0", code)
+	}
+}
+
+func TestCommandStartFailure(t *testing.T) {
+	// A directory with no go.mod and no go files: `go test ./...` still
+	// starts successfully but reports a non-zero exit code, not a Go error,
+	// so only a genuinely unstartable command (nonexistent dir) exercises
+	// the err return path.
+	code, err := Command(filepath.Join(t.TempDir(), "does-not-exist"), "test", []string{"./..."})
+	if err == nil {
+		t.Fatalf("Command in a nonexistent dir returned no error (code %d)", code)
+	}
+}
+
+func TestTestWrapperReportsFailure(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, `package main
+
+func main() {}
+`)
+	if err := os.WriteFile(filepath.Join(dir, "main_test.go"), []byte(`package main
+
+import "testing"
+
+func TestFails(t *testing.T) {
+	t.Fatal("boom")
+}
+`), 0666); err != nil {
+		t.Fatalf("write main_test.go: %v", err)
+	}
+
+	code, err := Test(dir, nil)
+	if err != nil {
+		t.Fatalf("Test: %v", err)
+	}
+	if code == 0 {
+		t.Fatalf("Test exit code = 0, want non-zero for a failing test")
+	}
+}
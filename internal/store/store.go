@@ -0,0 +1,226 @@
+// Package store persists metadata about playground directories GoPlay has
+// created. It replaces the old flat, append-only .generated_dirs file with
+// an indexed directory layout under $GOPLAY_DIR/index: each entry is a JSON
+// file at index/<xx>/<hash>.json, where <xx> is the first two hex digits of
+// the sha256 of the playground's path (256 shards), mirroring the layout
+// the Go toolchain itself uses for its build cache. Reads and writes are
+// guarded by a flock'd lock file so concurrent `goplay` invocations don't
+// race on the same entry.
+package store
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Entry is the metadata recorded for a single playground.
+type Entry struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	CreatedAt  time.Time `json:"created_at"`
+	Template   string    `json:"template"`
+	LastOpened time.Time `json:"last_opened"`
+}
+
+// Store is an index of Entry records rooted at a GoPlay home directory.
+type Store struct {
+	goplayDir string
+	indexDir  string
+	lockPath  string
+}
+
+// legacyFileName is the old flat index file this package replaces. Open
+// migrates it into the index on first run.
+const legacyFileName = ".generated_dirs"
+
+// Open returns the Store rooted at goplayDir, creating its index directory
+// and migrating any pre-existing .generated_dirs file into it if this is the
+// first time the index has been opened.
+func Open(goplayDir string) (*Store, error) {
+	s := &Store{
+		goplayDir: goplayDir,
+		indexDir:  filepath.Join(goplayDir, "index"),
+		lockPath:  filepath.Join(goplayDir, "index.lock"),
+	}
+
+	_, err := os.Stat(s.indexDir)
+	switch {
+	case err == nil:
+		return s, nil
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	if err := os.MkdirAll(s.indexDir, 0777); err != nil {
+		return nil, err
+	}
+	if err := s.migrateLegacy(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrateLegacy imports the old one-path-per-line .generated_dirs file into
+// the index, if one exists. The legacy file is left in place; it is simply
+// no longer consulted once the index exists.
+func (s *Store) migrateLegacy() error {
+	f, err := os.Open(filepath.Join(s.goplayDir, legacyFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		dirPath := strings.TrimSpace(scanner.Text())
+		if dirPath == "" {
+			continue
+		}
+		createdAt := time.Time{}
+		if markerInfo, err := os.Stat(filepath.Join(dirPath, ".goplay_marker")); err == nil {
+			createdAt = markerInfo.ModTime()
+		}
+		entry := Entry{
+			Name:      filepath.Base(dirPath),
+			Path:      dirPath,
+			CreatedAt: createdAt,
+		}
+		if err := s.Put(entry); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// shardedPath returns the index/<xx>/<hash>.json path for a playground path.
+func (s *Store) shardedPath(playgroundPath string) string {
+	sum := sha256.Sum256([]byte(playgroundPath))
+	hash := hex.EncodeToString(sum[:])
+	return filepath.Join(s.indexDir, hash[:2], hash+".json")
+}
+
+// withLock runs fn while holding an exclusive lock on the store's lock file.
+func (s *Store) withLock(fn func() error) error {
+	lockFile, err := os.OpenFile(s.lockPath, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	}
+	defer lockFile.Close()
+
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+
+	return fn()
+}
+
+// Put writes (or overwrites) an entry, keyed by entry.Path.
+func (s *Store) Put(entry Entry) error {
+	return s.withLock(func() error {
+		shardPath := s.shardedPath(entry.Path)
+		if err := os.MkdirAll(filepath.Dir(shardPath), 0777); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(shardPath, data, 0666)
+	})
+}
+
+// Touch updates an entry's LastOpened to now, leaving the rest unchanged. It
+// is a no-op if no entry exists for playgroundPath.
+func (s *Store) Touch(playgroundPath string) error {
+	return s.withLock(func() error {
+		shardPath := s.shardedPath(playgroundPath)
+		data, err := os.ReadFile(shardPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		entry.LastOpened = time.Now()
+		data, err = json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(shardPath, data, 0666)
+	})
+}
+
+// Remove deletes the entry for playgroundPath, if any.
+func (s *Store) Remove(playgroundPath string) error {
+	return s.withLock(func() error {
+		err := os.Remove(s.shardedPath(playgroundPath))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+}
+
+// List returns every entry in the index, ordered by CreatedAt ascending
+// (oldest first), matching the order playgrounds used to appear in
+// .generated_dirs.
+func (s *Store) List() ([]Entry, error) {
+	var entries []Entry
+	err := s.withLock(func() error {
+		return filepath.WalkDir(s.indexDir, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(p) != ".json" {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// MostRecent returns the entry with the latest CreatedAt, or false if the
+// index is empty.
+func (s *Store) MostRecent() (Entry, bool, error) {
+	entries, err := s.List()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if len(entries) == 0 {
+		return Entry{}, false, nil
+	}
+	return entries[len(entries)-1], true, nil
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPutListTouchRemove(t *testing.T) {
+	goplayDir := t.TempDir()
+	s, err := Open(goplayDir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	entryA := Entry{Name: "a", Path: filepath.Join(goplayDir, "a"), CreatedAt: time.Unix(1, 0)}
+	entryB := Entry{Name: "b", Path: filepath.Join(goplayDir, "b"), CreatedAt: time.Unix(2, 0)}
+	if err := s.Put(entryA); err != nil {
+		t.Fatalf("Put(a): %v", err)
+	}
+	if err := s.Put(entryB); err != nil {
+		t.Fatalf("Put(b): %v", err)
+	}
+
+	entries, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("List returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Name != "a" || entries[1].Name != "b" {
+		t.Fatalf("List not ordered oldest-first: %+v", entries)
+	}
+
+	if err := s.Touch(entryA.Path); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+	entries, err = s.List()
+	if err != nil {
+		t.Fatalf("List after Touch: %v", err)
+	}
+	if entries[0].LastOpened.IsZero() {
+		t.Fatalf("Touch did not set LastOpened: %+v", entries[0])
+	}
+
+	// Touch on an unknown path is a no-op, not an error.
+	if err := s.Touch(filepath.Join(goplayDir, "nonexistent")); err != nil {
+		t.Fatalf("Touch(unknown): %v", err)
+	}
+
+	if err := s.Remove(entryA.Path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	entries, err = s.List()
+	if err != nil {
+		t.Fatalf("List after Remove: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "b" {
+		t.Fatalf("List after Remove = %+v, want only entry b", entries)
+	}
+
+	// Removing an already-removed entry is a no-op, not an error.
+	if err := s.Remove(entryA.Path); err != nil {
+		t.Fatalf("Remove(already removed): %v", err)
+	}
+}
+
+func TestMostRecent(t *testing.T) {
+	goplayDir := t.TempDir()
+	s, err := Open(goplayDir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if _, ok, err := s.MostRecent(); err != nil || ok {
+		t.Fatalf("MostRecent on empty store = (_, %v, %v), want (_, false, nil)", ok, err)
+	}
+
+	older := Entry{Name: "older", Path: filepath.Join(goplayDir, "older"), CreatedAt: time.Unix(1, 0)}
+	newer := Entry{Name: "newer", Path: filepath.Join(goplayDir, "newer"), CreatedAt: time.Unix(2, 0)}
+	if err := s.Put(older); err != nil {
+		t.Fatalf("Put(older): %v", err)
+	}
+	if err := s.Put(newer); err != nil {
+		t.Fatalf("Put(newer): %v", err)
+	}
+
+	entry, ok, err := s.MostRecent()
+	if err != nil || !ok {
+		t.Fatalf("MostRecent = (_, %v, %v), want (_, true, nil)", ok, err)
+	}
+	if entry.Name != "newer" {
+		t.Fatalf("MostRecent = %+v, want newer", entry)
+	}
+}
+
+func TestShardedPathIsTwoHexPrefixed(t *testing.T) {
+	s := &Store{indexDir: "/index"}
+	p := s.shardedPath("/some/playground/path")
+
+	shard := filepath.Base(filepath.Dir(p))
+	if len(shard) != 2 {
+		t.Fatalf("shard dir %q, want 2 hex characters", shard)
+	}
+	base := filepath.Base(p)
+	if filepath.Ext(base) != ".json" {
+		t.Fatalf("shardedPath = %q, want a .json file", p)
+	}
+	if base[:2] != shard {
+		t.Fatalf("shardedPath %q, file name does not start with its shard prefix %q", p, shard)
+	}
+}
+
+func TestOpenMigratesLegacyFile(t *testing.T) {
+	goplayDir := t.TempDir()
+	playgroundDir := filepath.Join(goplayDir, "legacy-pg")
+	if err := os.MkdirAll(playgroundDir, 0777); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(playgroundDir, ".goplay_marker"), nil, 0666); err != nil {
+		t.Fatalf("write marker: %v", err)
+	}
+	legacy := playgroundDir + "\n\n" // blank lines should be skipped
+	if err := os.WriteFile(filepath.Join(goplayDir, legacyFileName), []byte(legacy), 0666); err != nil {
+		t.Fatalf("write legacy file: %v", err)
+	}
+
+	s, err := Open(goplayDir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	entries, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != playgroundDir {
+		t.Fatalf("List after migration = %+v, want one entry for %q", entries, playgroundDir)
+	}
+
+	// Opening again must not re-migrate or duplicate entries.
+	if _, err := Open(goplayDir); err != nil {
+		t.Fatalf("second Open: %v", err)
+	}
+	entries, err = s.List()
+	if err != nil {
+		t.Fatalf("List after second Open: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("List after second Open = %+v, want still one entry", entries)
+	}
+}
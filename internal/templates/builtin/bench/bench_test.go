@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func BenchmarkScratch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = i * i
+	}
+}
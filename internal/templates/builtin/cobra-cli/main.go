@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:   "app",
+		Short: "A scratch cobra-based CLI",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println("Hello from GoPlay!")
+		},
+	}
+
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import "testing"
+
+func FuzzScratch(f *testing.F) {
+	f.Add("seed")
+	f.Fuzz(func(t *testing.T, s string) {
+		_ = s
+	})
+}
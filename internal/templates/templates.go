@@ -0,0 +1,126 @@
+// Package templates resolves and materializes GoPlay starter templates.
+//
+// A template is a directory tree that gets copied into a new playground.
+// Templates are looked up by name, first under $GOPLAY_DIR/templates/<name>
+// (so users can drop in their own), falling back to the set of templates
+// embedded into this binary via go:embed. A template directory may include a
+// template.json manifest listing go.mod `require` directives that should be
+// added to the playground after `go mod init`.
+package templates
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+//go:embed builtin
+var builtinFS embed.FS
+
+const manifestFileName = "template.json"
+
+// DefaultName is used when the user does not pass -t.
+const DefaultName = "hello"
+
+// Manifest is the contents of a template's template.json.
+type Manifest struct {
+	// Require lists go.mod require directives (e.g. "github.com/spf13/cobra@latest")
+	// to add to the playground's go.mod after `go mod init`.
+	Require []string `json:"require"`
+}
+
+// resolve returns the fs.FS containing the named template's files, and the
+// path within that fs at which the template's directory tree is rooted.
+func resolve(goplayDir, name string) (fs.FS, string, error) {
+	userDir := filepath.Join(goplayDir, "templates", name)
+	if info, err := os.Stat(userDir); err == nil && info.IsDir() {
+		return os.DirFS(userDir), ".", nil
+	}
+
+	sub := path.Join("builtin", name)
+	if info, err := fs.Stat(builtinFS, sub); err != nil || !info.IsDir() {
+		return nil, "", fmt.Errorf("unknown template %q", name)
+	}
+	return builtinFS, sub, nil
+}
+
+// Materialize copies the named template's files into destDir, which must
+// already exist. Existing files in destDir are left untouched rather than
+// overwritten. It returns the template's manifest (the zero Manifest if the
+// template has no template.json).
+func Materialize(goplayDir, name, destDir string) (Manifest, error) {
+	root, sub, err := resolve(goplayDir, name)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var manifest Manifest
+	walkErr := fs.WalkDir(root, sub, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(sub, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(destDir, rel), 0777)
+		}
+		if rel == manifestFileName {
+			data, err := fs.ReadFile(root, p)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return fmt.Errorf("invalid %s in template %q: %w", manifestFileName, name, err)
+			}
+			return nil
+		}
+
+		src, err := root.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		dst, err := os.OpenFile(filepath.Join(destDir, rel), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0777)
+		if err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return nil // Leave the existing file alone, same as before templates existed.
+			}
+			return err
+		}
+		defer dst.Close()
+
+		_, err = io.Copy(dst, src)
+		return err
+	})
+	if walkErr != nil {
+		return Manifest{}, walkErr
+	}
+	return manifest, nil
+}
+
+// Names lists the built-in template names, for help text.
+func Names() []string {
+	entries, err := fs.ReadDir(builtinFS, "builtin")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}